@@ -0,0 +1,299 @@
+// Package anim provides a high-level animation engine for ZSA keyboard LEDs, built on top of
+// [github.com/bauersimon/go-zsa.Client]. Instead of driving LED colors from a manual clock loop, callers
+// declare one or more Timelines of keyframe Steps and hand them to an Engine to play, optionally
+// concurrently (see Engine.RunAll), with all tracks' writes coalesced per tick.
+package anim
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"sort"
+	"time"
+
+	"github.com/bauersimon/go-zsa"
+)
+
+// defaultFrameRate is the frame rate used when an Engine does not specify one.
+const defaultFrameRate = 30
+
+// Step describes a single keyframe transition: the given LEDs fade from From to To over Duration,
+// following Easing (Linear if nil).
+type Step struct {
+	LEDs     []int32
+	From     color.Color
+	To       color.Color
+	Duration time.Duration
+	Easing   Easing
+}
+
+// LoopMode controls how a Timeline repeats once its Steps have played through.
+type LoopMode int
+
+const (
+	// LoopNone plays the Timeline once and stops.
+	LoopNone LoopMode = iota
+	// LoopRepeat replays the Timeline from its first Step after the last one finishes.
+	LoopRepeat
+	// LoopPingPong replays the Timeline in reverse after the last Step finishes, then forwards again, and so on.
+	LoopPingPong
+)
+
+// Timeline is an ordered sequence of Steps to animate as a single track. Multiple Timelines can be played
+// concurrently with Engine.RunAll.
+type Timeline struct {
+	Steps []Step
+	Loop  LoopMode
+}
+
+// Engine plays Timelines against a Client at a fixed frame rate.
+type Engine struct {
+	Client *zsa.Client
+
+	// FrameRate is the number of frames rendered per second. Defaults to 30 if zero.
+	FrameRate int
+}
+
+// NewEngine creates an Engine driving the given client at the default frame rate.
+func NewEngine(client *zsa.Client) *Engine {
+	return &Engine{Client: client}
+}
+
+// frameInterval returns the configured tick interval, falling back to defaultFrameRate.
+func (e *Engine) frameInterval() time.Duration {
+	rate := e.FrameRate
+	if rate <= 0 {
+		rate = defaultFrameRate
+	}
+
+	return time.Second / time.Duration(rate)
+}
+
+// Run plays timeline until it completes (LoopNone) or ctx is done. It is a convenience wrapper around
+// RunAll for the common single-timeline case.
+func (e *Engine) Run(ctx context.Context, timeline Timeline) error {
+	return e.RunAll(ctx, timeline)
+}
+
+// RunAll plays several timelines concurrently as independent "tracks", ticking them together at the
+// engine's frame rate. Each tick, every track's current per-LED colors are collected and coalesced into
+// as few SetRGBLed batches as possible (one call per distinct color actually produced that tick), rather
+// than issuing a separate call per track. RunAll returns once every LoopNone track has finished playing,
+// or once ctx is done. Regardless of how RunAll returns, the LEDs touched by any timeline are cleared to
+// black as a final frame, so the keyboard is never left stuck mid-color.
+func (e *Engine) RunAll(ctx context.Context, timelines ...Timeline) error {
+	timelines = nonEmptyTimelines(timelines)
+	if len(timelines) == 0 {
+		return nil
+	}
+
+	defer func() {
+		_ = e.Client.SetRGBLed(context.Background(), color.Black, touchedLEDs(allSteps(timelines))...)
+	}()
+
+	ticker := time.NewTicker(e.frameInterval())
+	defer ticker.Stop()
+
+	now := time.Now()
+	tracks := make([]*trackState, len(timelines))
+	for i, timeline := range timelines {
+		tracks[i] = newTrackState(timeline, now)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := e.renderFrame(ctx, tracks); err != nil {
+			return err
+		}
+
+		if allFinished(tracks) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// renderFrame asks every track for its current per-LED colors, groups the LEDs of all tracks by the
+// resulting color, and issues one SetRGBLed batch per distinct color produced this tick.
+func (e *Engine) renderFrame(ctx context.Context, tracks []*trackState) error {
+	now := time.Now()
+
+	batches := map[color.Color][]int32{}
+	for _, track := range tracks {
+		for led, c := range track.frame(now) {
+			batches[c] = append(batches[c], led)
+		}
+	}
+
+	var errs []error
+	for c, leds := range batches {
+		if err := e.Client.SetRGBLed(ctx, c, leds...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// allFinished reports whether every track has finished playing (always false if any track loops forever).
+func allFinished(tracks []*trackState) bool {
+	for _, track := range tracks {
+		if !track.finished {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nonEmptyTimelines returns timelines with its stepless entries (nothing to animate) filtered out.
+func nonEmptyTimelines(timelines []Timeline) []Timeline {
+	out := make([]Timeline, 0, len(timelines))
+	for _, timeline := range timelines {
+		if len(timeline.Steps) > 0 {
+			out = append(out, timeline)
+		}
+	}
+
+	return out
+}
+
+// allSteps concatenates the steps of every timeline.
+func allSteps(timelines []Timeline) []Step {
+	var steps []Step
+	for _, timeline := range timelines {
+		steps = append(steps, timeline.Steps...)
+	}
+
+	return steps
+}
+
+// trackState is one concurrently-playing Timeline within an Engine.RunAll call.
+type trackState struct {
+	timeline  Timeline
+	stepIdx   int
+	forward   bool
+	stepStart time.Time
+	finished  bool
+}
+
+// newTrackState starts timeline playing forward from its first step at "now".
+func newTrackState(timeline Timeline, now time.Time) *trackState {
+	return &trackState{timeline: timeline, forward: true, stepStart: now}
+}
+
+// orderedSteps returns the track's steps in playback order for its current direction.
+func (s *trackState) orderedSteps() []Step {
+	if s.forward {
+		return s.timeline.Steps
+	}
+
+	return reversedSteps(s.timeline.Steps)
+}
+
+// frame returns the track's current per-LED colors at "now", advancing to the next step (and, once the
+// last step completes, the next loop iteration or direction) as needed. Once a LoopNone track finishes,
+// it holds its last frame and frame keeps returning it.
+func (s *trackState) frame(now time.Time) map[int32]color.Color {
+	steps := s.orderedSteps()
+	step := steps[s.stepIdx]
+	elapsed := now.Sub(s.stepStart)
+
+	t := 1.0
+	if step.Duration > 0 {
+		t = float64(elapsed) / float64(step.Duration)
+		if t > 1 {
+			t = 1
+		}
+	}
+	if !s.forward {
+		t = 1 - t
+	}
+
+	easing := step.Easing
+	if easing == nil {
+		easing = Linear
+	}
+
+	frame := lerpColor(step.From, step.To, easing(t))
+	colors := make(map[int32]color.Color, len(step.LEDs))
+	for _, led := range step.LEDs {
+		colors[led] = frame
+	}
+
+	if !s.finished && elapsed >= step.Duration {
+		if next := s.stepIdx + 1; next < len(steps) {
+			s.stepIdx = next
+			s.stepStart = now
+		} else {
+			switch s.timeline.Loop {
+			case LoopRepeat:
+				s.stepIdx = 0
+				s.stepStart = now
+			case LoopPingPong:
+				s.forward = !s.forward
+				s.stepIdx = 0
+				s.stepStart = now
+			default:
+				// Hold the last step's frame forever; stepIdx stays put so frame keeps returning its final color.
+				s.finished = true
+			}
+		}
+	}
+
+	return colors
+}
+
+// reversedSteps returns a copy of steps in reverse order.
+func reversedSteps(steps []Step) []Step {
+	out := make([]Step, len(steps))
+	for i, step := range steps {
+		out[len(steps)-1-i] = step
+	}
+
+	return out
+}
+
+// touchedLEDs returns the sorted, de-duplicated set of LEDs referenced by steps.
+func touchedLEDs(steps []Step) []int32 {
+	seen := map[int32]struct{}{}
+	var leds []int32
+	for _, step := range steps {
+		for _, led := range step.LEDs {
+			if _, ok := seen[led]; ok {
+				continue
+			}
+			seen[led] = struct{}{}
+			leds = append(leds, led)
+		}
+	}
+
+	sort.Slice(leds, func(i, j int) bool { return leds[i] < leds[j] })
+	return leds
+}
+
+// lerpColor interpolates from "from" to "to" at position t (expected in [0,1]), in the same 16-bit-per-channel
+// range returned by color.Color.RGBA.
+func lerpColor(from, to color.Color, t float64) color.Color {
+	fr, fg, fb, fa := from.RGBA()
+	tr, tg, tb, ta := to.RGBA()
+
+	return color.RGBA64{
+		R: lerp16(fr, tr, t),
+		G: lerp16(fg, tg, t),
+		B: lerp16(fb, tb, t),
+		A: lerp16(fa, ta, t),
+	}
+}
+
+func lerp16(from, to uint32, t float64) uint16 {
+	return uint16(float64(from) + (float64(to)-float64(from))*t)
+}
@@ -0,0 +1,130 @@
+package anim
+
+import (
+	"image/color"
+	"testing"
+	"time"
+)
+
+func TestTrackStateFrameInterpolatesWithinAStep(t *testing.T) {
+	start := time.Unix(0, 0)
+	track := newTrackState(Timeline{
+		Steps: []Step{
+			{LEDs: []int32{1}, From: color.Black, To: color.White, Duration: 100 * time.Millisecond},
+		},
+		Loop: LoopNone,
+	}, start)
+
+	colors := track.frame(start.Add(50 * time.Millisecond))
+	r, _, _, _ := colors[1].RGBA()
+	if r < 0x7000 || r > 0x9000 {
+		t.Fatalf("expected ~halfway red channel at the midpoint, got %#x", r)
+	}
+	if track.finished {
+		t.Fatal("track should not be finished mid-step")
+	}
+}
+
+func TestTrackStateFrameAdvancesToNextStep(t *testing.T) {
+	start := time.Unix(0, 0)
+	track := newTrackState(Timeline{
+		Steps: []Step{
+			{LEDs: []int32{1}, From: color.Black, To: color.White, Duration: 100 * time.Millisecond},
+			{LEDs: []int32{1}, From: color.White, To: color.Black, Duration: 100 * time.Millisecond},
+		},
+		Loop: LoopNone,
+	}, start)
+
+	track.frame(start.Add(100 * time.Millisecond))
+	if track.stepIdx != 1 {
+		t.Fatalf("expected stepIdx 1 after the first step completes, got %d", track.stepIdx)
+	}
+	if track.finished {
+		t.Fatal("track should not be finished after only its first step")
+	}
+}
+
+func TestTrackStateFrameFinishesAndHoldsLastStepOnLoopNone(t *testing.T) {
+	start := time.Unix(0, 0)
+	track := newTrackState(Timeline{
+		Steps: []Step{
+			{LEDs: []int32{1}, From: color.Black, To: color.White, Duration: 100 * time.Millisecond},
+			{LEDs: []int32{1}, From: color.White, To: color.Black, Duration: 100 * time.Millisecond},
+		},
+		Loop: LoopNone,
+	}, start)
+
+	track.frame(start.Add(100 * time.Millisecond))
+	colors := track.frame(start.Add(200 * time.Millisecond))
+	if !track.finished {
+		t.Fatal("expected track to be finished after its last step completes")
+	}
+	if track.stepIdx != 1 {
+		t.Fatalf("expected the finished track to hold at its last step (index 1), got %d", track.stepIdx)
+	}
+
+	r, g, b, _ := colors[1].RGBA()
+	if r != 0 || g != 0 || b != 0 {
+		t.Fatalf("expected the finished track to hold black (the last step's To), got %#v", colors[1])
+	}
+
+	// Further frames keep holding the same color instead of drifting.
+	again := track.frame(start.Add(500 * time.Millisecond))
+	if again[1] != colors[1] {
+		t.Fatalf("expected a finished track to keep holding its final frame, got %#v vs %#v", again[1], colors[1])
+	}
+}
+
+func TestTrackStateFrameLoopsRepeat(t *testing.T) {
+	start := time.Unix(0, 0)
+	track := newTrackState(Timeline{
+		Steps: []Step{
+			{LEDs: []int32{1}, From: color.Black, To: color.White, Duration: 100 * time.Millisecond},
+		},
+		Loop: LoopRepeat,
+	}, start)
+
+	track.frame(start.Add(100 * time.Millisecond))
+	if track.finished {
+		t.Fatal("a LoopRepeat track should never finish")
+	}
+	if track.stepIdx != 0 {
+		t.Fatalf("expected stepIdx to reset to 0 on repeat, got %d", track.stepIdx)
+	}
+}
+
+func TestTrackStateFramePingPongFlipsDirection(t *testing.T) {
+	start := time.Unix(0, 0)
+	track := newTrackState(Timeline{
+		Steps: []Step{
+			{LEDs: []int32{1}, From: color.Black, To: color.White, Duration: 100 * time.Millisecond},
+			{LEDs: []int32{1}, From: color.White, To: color.Black, Duration: 100 * time.Millisecond},
+		},
+		Loop: LoopPingPong,
+	}, start)
+
+	track.frame(start.Add(100 * time.Millisecond))
+	track.frame(start.Add(200 * time.Millisecond))
+	if track.forward {
+		t.Fatal("expected direction to flip to backward after a full pass")
+	}
+	if track.stepIdx != 0 {
+		t.Fatalf("expected stepIdx to reset to 0 after flipping direction, got %d", track.stepIdx)
+	}
+}
+
+func TestTrackStateFrameZeroDurationStepAdvancesImmediately(t *testing.T) {
+	start := time.Unix(0, 0)
+	track := newTrackState(Timeline{
+		Steps: []Step{
+			{LEDs: []int32{1}, From: color.Black, To: color.Black, Duration: 0},
+			{LEDs: []int32{1}, From: color.White, To: color.White, Duration: 100 * time.Millisecond},
+		},
+		Loop: LoopNone,
+	}, start)
+
+	track.frame(start)
+	if track.stepIdx != 1 {
+		t.Fatalf("expected a zero-duration step to advance on the very next frame, got stepIdx %d", track.stepIdx)
+	}
+}
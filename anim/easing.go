@@ -0,0 +1,30 @@
+package anim
+
+import "math"
+
+// Easing maps a normalized progress value t in [0,1] to an eased progress value, also expected in [0,1].
+type Easing func(t float64) float64
+
+// Linear is the identity easing function.
+func Linear(t float64) float64 {
+	return t
+}
+
+// EaseIn starts slow and accelerates towards the end.
+func EaseIn(t float64) float64 {
+	return t * t
+}
+
+// EaseOut starts fast and decelerates towards the end.
+func EaseOut(t float64) float64 {
+	return 1 - (1-t)*(1-t)
+}
+
+// EaseInOut accelerates out of the start and decelerates into the end.
+func EaseInOut(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+
+	return 1 - math.Pow(-2*t+2, 2)/2
+}
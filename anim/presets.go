@@ -0,0 +1,86 @@
+package anim
+
+import (
+	"image/color"
+	"math"
+	"time"
+)
+
+// Pulse returns a Timeline that fades leds from off to c and back, looping indefinitely.
+func Pulse(leds []int32, c color.Color, duration time.Duration) Timeline {
+	return Timeline{
+		Steps: []Step{
+			{LEDs: leds, From: color.Black, To: c, Duration: duration, Easing: EaseInOut},
+		},
+		Loop: LoopPingPong,
+	}
+}
+
+// Blink returns a Timeline that snaps leds between off and c every interval, looping indefinitely.
+func Blink(leds []int32, c color.Color, interval time.Duration) Timeline {
+	return Timeline{
+		Steps: []Step{
+			{LEDs: leds, From: c, To: c, Duration: interval, Easing: Linear},
+			{LEDs: leds, From: color.Black, To: color.Black, Duration: interval, Easing: Linear},
+		},
+		Loop: LoopRepeat,
+	}
+}
+
+// RainbowSweep returns one Timeline per LED; playing them together with Engine.RunAll chases a
+// hue-cycling pulse across leds in order, looping indefinitely. Each LED's Timeline fades in on its turn,
+// fades back out to black on the next, and holds off for the rest of the cycle, so at most two LEDs are
+// lit at once (one fading in while the previous one fades out), instead of every LED that has already had
+// its turn staying lit.
+func RainbowSweep(leds []int32, stepDuration time.Duration) []Timeline {
+	n := len(leds)
+	timelines := make([]Timeline, n)
+	for i, led := range leds {
+		hue := hueColor(float64(i) / float64(n))
+
+		steps := make([]Step, n)
+		for j := range steps {
+			switch j {
+			case i:
+				steps[j] = Step{LEDs: []int32{led}, From: color.Black, To: hue, Duration: stepDuration, Easing: EaseInOut}
+			case (i + 1) % n:
+				steps[j] = Step{LEDs: []int32{led}, From: hue, To: color.Black, Duration: stepDuration, Easing: EaseInOut}
+			default:
+				steps[j] = Step{LEDs: []int32{led}, From: color.Black, To: color.Black, Duration: stepDuration}
+			}
+		}
+
+		timelines[i] = Timeline{Steps: steps, Loop: LoopRepeat}
+	}
+
+	return timelines
+}
+
+// hueColor converts a hue in [0,1) at full saturation and value to an RGB color.
+func hueColor(hue float64) color.Color {
+	h := hue * 6
+	x := 1 - math.Abs(math.Mod(h, 2)-1)
+
+	var r, g, b float64
+	switch {
+	case h < 1:
+		r, g, b = 1, x, 0
+	case h < 2:
+		r, g, b = x, 1, 0
+	case h < 3:
+		r, g, b = 0, 1, x
+	case h < 4:
+		r, g, b = 0, x, 1
+	case h < 5:
+		r, g, b = x, 0, 1
+	default:
+		r, g, b = 1, 0, x
+	}
+
+	return color.RGBA64{
+		R: uint16(r * 0xffff),
+		G: uint16(g * 0xffff),
+		B: uint16(b * 0xffff),
+		A: 0xffff,
+	}
+}
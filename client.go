@@ -9,35 +9,118 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/bauersimon/go-zsa/api"
 )
 
+// defaultMaxInFlight is the default number of concurrent requests issued by SetRGBLeds.
+const defaultMaxInFlight = 8
+
 // Client represents a connection to the ZSA keyboard service.
 type Client struct {
 	client     api.KeyboardServiceClient
 	connection *grpc.ClientConn
+
+	maxInFlight    int
+	requestTimeout time.Duration
+}
+
+// clientOptions accumulates the effect of Option values, both the grpc.DialOptions applied while
+// connecting and the settings applied to the Client afterwards.
+type clientOptions struct {
+	dialOptions    []grpc.DialOption
+	hasCredentials bool
+	maxInFlight    int
+	requestTimeout time.Duration
+}
+
+// Option configures a Client created by Connect, ConnectDefault, or ConnectWithOptions.
+type Option func(*clientOptions)
+
+// WithMaxInFlight sets the maximum number of concurrent SetRGBLed calls issued by SetRGBLeds.
+// Defaults to 8.
+func WithMaxInFlight(n int) Option {
+	return func(o *clientOptions) {
+		o.maxInFlight = n
+	}
+}
+
+// WithUnaryInterceptor adds a unary client interceptor to the underlying gRPC connection, e.g. for
+// logging, tracing, or rate-limiting the calls driving animations. Interceptors run in the order they
+// are given.
+func WithUnaryInterceptor(interceptor grpc.UnaryClientInterceptor) Option {
+	return func(o *clientOptions) {
+		o.dialOptions = append(o.dialOptions, grpc.WithChainUnaryInterceptor(interceptor))
+	}
+}
+
+// WithDialOption adds a raw grpc.DialOption to the underlying connection, for cases not covered by a
+// dedicated Option.
+func WithDialOption(dialOption grpc.DialOption) Option {
+	return func(o *clientOptions) {
+		o.dialOptions = append(o.dialOptions, dialOption)
+	}
+}
+
+// WithTransportCredentials overrides the default insecure transport credentials used by Connect.
+func WithTransportCredentials(creds credentials.TransportCredentials) Option {
+	return func(o *clientOptions) {
+		o.hasCredentials = true
+		o.dialOptions = append(o.dialOptions, grpc.WithTransportCredentials(creds))
+	}
+}
+
+// WithRequestTimeout wraps every high-level Client method (SetRGBAll, SetLayer, etc.) in a context
+// derived from the caller's with the given timeout, unless the caller's context already carries an
+// earlier deadline.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(o *clientOptions) {
+		o.requestTimeout = d
+	}
+}
+
+// Connect establishes a connection to the ZSA keyboard service at the specified path or address. It is
+// equivalent to ConnectWithOptions; without opts it uses insecure transport credentials and no interceptors.
+func Connect(path string, opts ...Option) (*Client, error) {
+	return ConnectWithOptions(path, opts...)
 }
 
-// Connect establishes a connection to the ZSA keyboard service at the specified path or address.
-func Connect(path string) (*Client, error) {
-	conn, err := grpc.NewClient(path, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// ConnectWithOptions establishes a connection like Connect, but allows injecting dial options, unary
+// interceptors, custom transport credentials, and Client-side behavior via opts. Without
+// WithTransportCredentials, the connection falls back to insecure transport credentials.
+func ConnectWithOptions(path string, opts ...Option) (*Client, error) {
+	cfg := &clientOptions{maxInFlight: defaultMaxInFlight}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	dialOptions := cfg.dialOptions
+	if !cfg.hasCredentials {
+		dialOptions = append(dialOptions, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	conn, err := grpc.NewClient(path, dialOptions...)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Client{
-		client:     api.NewKeyboardServiceClient(conn),
-		connection: conn,
+		client:         api.NewKeyboardServiceClient(conn),
+		connection:     conn,
+		maxInFlight:    cfg.maxInFlight,
+		requestTimeout: cfg.requestTimeout,
 	}, nil
 }
 
 // ConnectDefault establishes a connection to the ZSA keyboard service using default settings.
 // On Windows, it connects to "localhost:50051", on other platforms, it uses the socket file at "$CONFIG_DIR/.keymapp/keymapp.sock" (as specified by https://github.com/zsa/kontroll?tab=readme-ov-file#prerequisites).
-func ConnectDefault() (*Client, error) {
+func ConnectDefault(opts ...Option) (*Client, error) {
 	path := ""
 	if runtime.GOOS == "windows" {
 		path = "localhost:50051"
@@ -49,12 +132,28 @@ func ConnectDefault() (*Client, error) {
 		path = filepath.Join(config_dir, ".keymapp", "keymapp.sock")
 	}
 
-	return Connect(path)
+	return Connect(path, opts...)
+}
+
+// withTimeout derives a context bounded by the client's configured request timeout (see
+// WithRequestTimeout), unless ctx already carries an equal or earlier deadline.
+func (c *Client) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.requestTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= c.requestTimeout {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, c.requestTimeout)
 }
 
 // GetStatus retrieves the current status of the keyboard service.
 // The returned keyboard might be "nil" in case none is currently connected.
 func (c *Client) GetStatus(ctx context.Context) (version string, keyboard *api.ConnectedKeyboard, err error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	res, err := c.client.GetStatus(ctx, &api.GetStatusRequest{})
 	if err != nil {
 		return "", nil, err
@@ -65,6 +164,9 @@ func (c *Client) GetStatus(ctx context.Context) (version string, keyboard *api.C
 
 // GetKeyboards retrieves a list of all detected keyboards.
 func (c *Client) GetKeyboards(ctx context.Context) (keyboards []*api.Keyboard, err error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	res, err := c.client.GetKeyboards(ctx, &api.GetKeyboardsRequest{})
 	if err != nil {
 		return nil, err
@@ -75,6 +177,9 @@ func (c *Client) GetKeyboards(ctx context.Context) (keyboards []*api.Keyboard, e
 
 // ConnectAnyKeyboard attempts to connect to an arbitrary available keyboard.
 func (c *Client) ConnectAnyKeyboard(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	err := wrapSuccessToError(ctx, c.client.ConnectAnyKeyboard, &api.ConnectAnyKeyboardRequest{})
 	if err != nil && !strings.Contains(err.Error(), "keyboard already connected") {
 		return err
@@ -85,6 +190,9 @@ func (c *Client) ConnectAnyKeyboard(ctx context.Context) error {
 
 // ConnectKeyboardIndex connects to a specific keyboard by its index.
 func (c *Client) ConnectKeyboardIndex(ctx context.Context, id int32) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	err := wrapSuccessToError(ctx, c.client.ConnectKeyboard, &api.ConnectKeyboardRequest{
 		Id: id,
 	})
@@ -102,6 +210,9 @@ func (c *Client) ConnectKeyboard(ctx context.Context, keyboard *api.Keyboard) er
 
 // DisconnectKeyboard disconnects from the currently connected keyboard.
 func (c *Client) DisconnectKeyboard(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	err := wrapSuccessToError(ctx, c.client.DisconnectKeyboard, &api.DisconnectKeyboardRequest{})
 	if err != nil && !strings.Contains(err.Error(), "no keyboard is connected") {
 		return err
@@ -112,6 +223,9 @@ func (c *Client) DisconnectKeyboard(ctx context.Context) error {
 
 // SetLayer sets the active layer of the connected keyboard.
 func (c *Client) SetLayer(ctx context.Context, layer int32) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	return wrapSuccessToError(ctx, c.client.SetLayer, &api.SetLayerRequest{
 		Layer: layer,
 	})
@@ -119,6 +233,9 @@ func (c *Client) SetLayer(ctx context.Context, layer int32) error {
 
 // UnsetLayer unsets a previously set layer.
 func (c *Client) UnsetLayer(ctx context.Context, layer int32) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	return wrapSuccessToError(ctx, c.client.UnsetLayer, &api.SetLayerRequest{
 		Layer: layer,
 	})
@@ -127,6 +244,9 @@ func (c *Client) UnsetLayer(ctx context.Context, layer int32) error {
 // SetRGBLed sets the color of a specific LED on the keyboard.
 // Each additional specified LED tirggers a separate API request. To change all LEDs at once, use "SetRGBAll".
 func (c *Client) SetRGBLed(ctx context.Context, color color.Color, leds ...int32) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	r, g, b, _ := color.RGBA()
 	var errs []error
 	for _, led := range leds {
@@ -144,8 +264,63 @@ func (c *Client) SetRGBLed(ctx context.Context, color color.Color, leds ...int32
 	return errors.Join(errs...)
 }
 
+// SetRGBLeds sets the color of multiple LEDs, each with its own color, issuing the underlying per-LED
+// requests concurrently across a bounded worker pool (see WithMaxInFlight). Errors from individual LEDs
+// are joined together; if ctx is cancelled, LEDs not yet sent are abandoned and the resulting error is
+// joined in as well.
+func (c *Client) SetRGBLeds(ctx context.Context, colors map[int32]color.Color) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	maxInFlight := c.maxInFlight
+	if maxInFlight <= 0 {
+		maxInFlight = defaultMaxInFlight
+	}
+
+	sem := make(chan struct{}, maxInFlight)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	cancelled := false
+	for led, col := range colors {
+		if cancelled {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			cancelled = true
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(led int32, col color.Color) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := c.SetRGBLed(ctx, col, led); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(led, col)
+	}
+
+	wg.Wait()
+	if cancelled {
+		errs = append(errs, ctx.Err())
+	}
+
+	return errors.Join(errs...)
+}
+
 // SetRGBAll sets the color of all LEDs on the keyboard.
 func (c *Client) SetRGBAll(ctx context.Context, color color.Color) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	r, g, b, _ := color.RGBA()
 	return wrapSuccessToError(ctx, c.client.SetRGBAll, &api.SetRGBAllRequest{
 		Red:     int32(r),
@@ -157,6 +332,9 @@ func (c *Client) SetRGBAll(ctx context.Context, color color.Color) error {
 
 // SetStatusLED sets the status LED on the keyboard.
 func (c *Client) SetStatusLED(ctx context.Context, led int32, on bool) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	return wrapSuccessToError(ctx, c.client.SetStatusLed, &api.SetStatusLedRequest{
 		Led:     led,
 		On:      on,
@@ -166,11 +344,17 @@ func (c *Client) SetStatusLED(ctx context.Context, led int32, on bool) error {
 
 // IncreaseBrightness increases the brightness of the keyboard.
 func (c *Client) IncreaseBrightness(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	return wrapSuccessToError(ctx, c.client.IncreaseBrightness, &api.IncreaseBrightnessRequest{})
 }
 
 // DecreaseBrightness decreases the brightness of the keyboard.
 func (c *Client) DecreaseBrightness(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
 	return wrapSuccessToError(ctx, c.client.DecreaseBrightness, &api.DecreaseBrightnessRequest{})
 }
 
@@ -0,0 +1,116 @@
+package zsa
+
+import (
+	"context"
+	"errors"
+	"image/color"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/bauersimon/go-zsa/api"
+)
+
+// fakeKeyboardServiceClient implements api.KeyboardServiceClient by embedding the (nil) interface and
+// overriding only the methods a test needs; any other method panics if called, which is fine since these
+// tests never call them.
+type fakeKeyboardServiceClient struct {
+	api.KeyboardServiceClient
+
+	setRGBLed func(ctx context.Context, in *api.SetRGBLedRequest, opts ...grpc.CallOption) (*api.SetRGBLedResponse, error)
+}
+
+func (f *fakeKeyboardServiceClient) SetRGBLed(ctx context.Context, in *api.SetRGBLedRequest, opts ...grpc.CallOption) (*api.SetRGBLedResponse, error) {
+	return f.setRGBLed(ctx, in, opts...)
+}
+
+func TestSetRGBLedsRespectsMaxInFlight(t *testing.T) {
+	const maxInFlight = 2
+	const totalLEDs = 5
+
+	started := make(chan struct{}, totalLEDs)
+	proceed := make(chan struct{})
+
+	client := &Client{
+		maxInFlight: maxInFlight,
+		client: &fakeKeyboardServiceClient{
+			setRGBLed: func(ctx context.Context, in *api.SetRGBLedRequest, opts ...grpc.CallOption) (*api.SetRGBLedResponse, error) {
+				started <- struct{}{}
+				<-proceed
+				return &api.SetRGBLedResponse{Success: true}, nil
+			},
+		},
+	}
+
+	colors := make(map[int32]color.Color, totalLEDs)
+	for i := int32(1); i <= totalLEDs; i++ {
+		colors[i] = color.Black
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- client.SetRGBLeds(context.Background(), colors) }()
+
+	for i := 0; i < maxInFlight; i++ {
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatalf("expected %d calls to start within the worker pool limit", maxInFlight)
+		}
+	}
+
+	select {
+	case <-started:
+		t.Fatalf("more than %d calls started concurrently", maxInFlight)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(proceed)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSetRGBLedsJoinsErrors(t *testing.T) {
+	wantErr := errors.New("boom")
+
+	client := &Client{
+		maxInFlight: 4,
+		client: &fakeKeyboardServiceClient{
+			setRGBLed: func(ctx context.Context, in *api.SetRGBLedRequest, opts ...grpc.CallOption) (*api.SetRGBLedResponse, error) {
+				return nil, wantErr
+			},
+		},
+	}
+
+	err := client.SetRGBLeds(context.Background(), map[int32]color.Color{1: color.Black, 2: color.Black})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected the joined error to wrap %v, got %v", wantErr, err)
+	}
+}
+
+func TestSetRGBLedsHonorsCancellation(t *testing.T) {
+	client := &Client{
+		maxInFlight: 1,
+		client: &fakeKeyboardServiceClient{
+			setRGBLed: func(ctx context.Context, in *api.SetRGBLedRequest, opts ...grpc.CallOption) (*api.SetRGBLedResponse, error) {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+				return &api.SetRGBLedResponse{Success: true}, nil
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.SetRGBLeds(ctx, map[int32]color.Color{1: color.Black, 2: color.Black, 3: color.Black})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package zsa
+
+import (
+	"context"
+	"image/color"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultReconnectBackoff is the delay used between connection attempts when RunOptions.ReconnectBackoff
+// is unset.
+const defaultReconnectBackoff = time.Second
+
+// RunOptions configures Run.
+type RunOptions struct {
+	// Path is forwarded to Connect. If empty, ConnectDefault is used instead.
+	Path string
+
+	// Options are forwarded to the underlying Connect/ConnectDefault call.
+	Options []Option
+
+	// ReconnectBackoff is the delay between connection attempts, both for the initial connect and, if
+	// KeepAlive is set, for reconnecting after the keymapp socket drops. Defaults to one second.
+	ReconnectBackoff time.Duration
+
+	// OnKeyboardLost, if set, is called with the triggering error whenever KeepAlive is set and the keymapp
+	// connection is lost, before a reconnect is attempted. Without KeepAlive it is not called.
+	OnKeyboardLost func(error)
+
+	// KeepAlive transparently reconnects if the keymapp socket drops mid-session, instead of returning
+	// the raw gRPC error from fn.
+	KeepAlive bool
+}
+
+// Run connects to the ZSA keyboard service, invokes fn with the connected Client, and tears the connection
+// down cleanly once fn returns: the keyboard is disconnected, its LEDs are cleared, and the gRPC connection
+// is closed. Run does not interrupt fn when ctx is done (e.g. a ctx from
+// signal.NotifyContext(ctx, os.Interrupt)); the teardown happens once fn returns. The initial
+// connection is retried with RunOptions.ReconnectBackoff if keymapp isn't up yet. If RunOptions.KeepAlive
+// is set, a dropped keymapp socket is reconnected transparently instead of being returned from Run.
+func Run(ctx context.Context, opts RunOptions, fn func(*Client) error) error {
+	if opts.ReconnectBackoff <= 0 {
+		opts.ReconnectBackoff = defaultReconnectBackoff
+	}
+
+	client, err := connectWithRetry(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() { shutdown(client) }()
+
+	for {
+		err := fn(client)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if !opts.KeepAlive || !isConnectionError(err) {
+			return err
+		}
+
+		if opts.OnKeyboardLost != nil {
+			opts.OnKeyboardLost(err)
+		}
+
+		client.Close()
+		next, err := connectWithRetry(ctx, opts)
+		if err != nil {
+			return err
+		}
+		client = next
+	}
+}
+
+// connectWithRetry connects and selects a keyboard, retrying with opts.ReconnectBackoff until both steps
+// succeed or ctx is done. This is what actually surfaces "keymapp isn't up yet": dialing itself rarely
+// fails since grpc.NewClient dials lazily, but the following ConnectAnyKeyboard call does, so it has to be
+// retried too, not just the dial.
+func connectWithRetry(ctx context.Context, opts RunOptions) (*Client, error) {
+	for {
+		client, err := tryConnect(ctx, opts)
+		if err == nil {
+			return client, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(opts.ReconnectBackoff):
+		}
+	}
+}
+
+// tryConnect makes a single connection attempt: dial, then select a keyboard.
+func tryConnect(ctx context.Context, opts RunOptions) (*Client, error) {
+	client, err := connect(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.ConnectAnyKeyboard(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// connect dials according to opts.Path, falling back to ConnectDefault when it is unset.
+func connect(opts RunOptions) (*Client, error) {
+	if opts.Path == "" {
+		return ConnectDefault(opts.Options...)
+	}
+
+	return Connect(opts.Path, opts.Options...)
+}
+
+// shutdown clears the keyboard's LEDs, disconnects it, and closes client, best-effort. LEDs are cleared
+// before disconnecting since SetRGBAll acts on the connected keyboard and would be a no-op afterwards.
+func shutdown(client *Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_ = client.SetRGBAll(ctx, color.Black)
+	_ = client.DisconnectKeyboard(ctx)
+	client.Close()
+}
+
+// isConnectionError reports whether err looks like the keymapp gRPC socket having dropped, as opposed to
+// an ordinary application-level error from fn.
+func isConnectionError(err error) bool {
+	st, ok := status.FromError(err)
+	return ok && st.Code() == codes.Unavailable
+}